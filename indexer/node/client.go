@@ -2,12 +2,18 @@ package node
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"math/big"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/ethereum-optimism/optimism/op-service/client"
+	"github.com/ethereum-optimism/optimism/op-service/eth"
 	"github.com/ethereum-optimism/optimism/op-service/retry"
 
 	"github.com/ethereum/go-ethereum"
@@ -15,6 +21,8 @@ import (
 	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/rpc"
+
+	"golang.org/x/sync/singleflight"
 )
 
 const (
@@ -29,6 +37,22 @@ const (
 	// defaultRequestTimeout is the default duration the processor will
 	// wait for a request to be fulfilled
 	defaultRequestTimeout = 10 * time.Second
+
+	// defaultRequestAttempts is the default attempts a retryable request
+	// will be made before failing
+	defaultRequestAttempts = 3
+
+	// defaultHeaderBatchSize is the maximum number of headers requested
+	// within a single batch call
+	defaultHeaderBatchSize = 100
+
+	// defaultReceiptBatchSize is the maximum number of transaction or block
+	// receipt requests within a single batch call
+	defaultReceiptBatchSize = 100
+
+	// defaultMaxIdleConnsPerHost is the default number of idle HTTP connections
+	// kept alive to the backend for reuse by concurrent requests
+	defaultMaxIdleConnsPerHost = 64
 )
 
 type EthClient interface {
@@ -38,8 +62,9 @@ type EthClient interface {
 
 	TxByHash(common.Hash) (*types.Transaction, error)
 
-	StorageHash(common.Address, *big.Int) (common.Hash, error)
+	StorageProof(common.Address, []common.Hash, common.Hash) (*eth.AccountResult, error)
 	FilterLogs(ethereum.FilterQuery) (Logs, error)
+	FilteredReceipts(*big.Int, *big.Int, []common.Address, [][]common.Hash) ([]*types.Receipt, error)
 
 	// Close closes the underlying RPC connection.
 	// RPC close does not return any errors, but does shut down e.g. a websocket connection.
@@ -47,7 +72,8 @@ type EthClient interface {
 }
 
 type clnt struct {
-	rpc RPC
+	rpc  RPC
+	bOff retry.Strategy
 }
 
 func DialEthClient(ctx context.Context, rpcUrl string, metrics Metricer) (EthClient, error) {
@@ -60,7 +86,9 @@ func DialEthClient(ctx context.Context, rpcUrl string, metrics Metricer) (EthCli
 			return nil, fmt.Errorf("address unavailable (%s)", rpcUrl)
 		}
 
-		client, err := rpc.DialContext(ctx, rpcUrl)
+		transport := http.DefaultTransport.(*http.Transport).Clone()
+		transport.MaxIdleConnsPerHost = defaultMaxIdleConnsPerHost
+		client, err := rpc.DialOptions(ctx, rpcUrl, rpc.WithHTTPClient(&http.Client{Transport: transport}))
 		if err != nil {
 			return nil, fmt.Errorf("failed to dial address (%s): %w", rpcUrl, err)
 		}
@@ -72,7 +100,17 @@ func DialEthClient(ctx context.Context, rpcUrl string, metrics Metricer) (EthCli
 		return nil, err
 	}
 
-	return &clnt{rpc: NewRPC(rpcClient, metrics)}, nil
+	return &clnt{rpc: NewRPC(rpcClient, metrics.WithEndpoint(endpointLabel(rpcUrl))), bOff: retry.Exponential()}, nil
+}
+
+// endpointLabel returns the host of the supplied url, omitting any
+// path or credentials, to label metrics with
+func endpointLabel(rpcUrl string) string {
+	u, err := url.Parse(rpcUrl)
+	if err != nil || u.Host == "" {
+		return defaultEndpoint
+	}
+	return u.Host
 }
 
 // BlockHeaderByHash retrieves the block header attributed to the supplied hash
@@ -114,7 +152,10 @@ func (c *clnt) BlockHeaderByNumber(number *big.Int) (*types.Header, error) {
 
 // BlockHeadersByRange will retrieve block headers within the specified range -- inclusive. No restrictions
 // are placed on the range such as blocks in the "latest", "safe" or "finalized" states. If the specified
-// range is too large, `endHeight > latest`, the resulting list is truncated to the available headers
+// range is too large, `endHeight > latest`, the resulting list is truncated to the available headers.
+//
+// The range is fetched in batches of at most `defaultHeaderBatchSize` headers. Failed batch requests are
+// retried with backoff, so large ranges can be scanned without exceeding provider batch limits
 func (c *clnt) BlockHeadersByRange(startHeight, endHeight *big.Int) ([]types.Header, error) {
 	// avoid the batch call if there's no range
 	if startHeight.Cmp(endHeight) == 0 {
@@ -126,7 +167,37 @@ func (c *clnt) BlockHeadersByRange(startHeight, endHeight *big.Int) ([]types.Hea
 	}
 
 	count := new(big.Int).Sub(endHeight, startHeight).Uint64() + 1
-	headers := make([]types.Header, count)
+	headers := make([]types.Header, 0, count)
+	for offset := uint64(0); offset < count; offset += defaultHeaderBatchSize {
+		batchStart := new(big.Int).Add(startHeight, new(big.Int).SetUint64(offset))
+		batchCount := min(count-offset, defaultHeaderBatchSize)
+
+		batch, err := c.blockHeadersBatch(batchStart, batchCount)
+		if err != nil {
+			if len(headers) > 0 {
+				break // try return whatever headers are available
+			}
+			return nil, err
+		}
+
+		if len(batch) > 0 && len(headers) > 0 && batch[0].ParentHash != headers[len(headers)-1].Hash() {
+			return nil, fmt.Errorf("queried header %s does not follow parent %s", batch[0].Hash(), headers[len(headers)-1].Hash())
+		}
+
+		headers = append(headers, batch...)
+		if uint64(len(batch)) < batchCount {
+			break // truncated to the available headers
+		}
+	}
+
+	return headers, nil
+}
+
+// blockHeadersBatch retrieves `count` block headers starting from `startHeight` within a single batch call.
+// Only failures of the batch request itself are retried. Errors of individual elements, such as headers
+// not found past the head of the chain, are not.
+func (c *clnt) blockHeadersBatch(startHeight *big.Int, count uint64) ([]types.Header, error) {
+	headers := make([]*types.Header, count)
 	batchElems := make([]rpc.BatchElem, count)
 
 	for i := uint64(0); i < count; i++ {
@@ -134,9 +205,11 @@ func (c *clnt) BlockHeadersByRange(startHeight, endHeight *big.Int) ([]types.Hea
 		batchElems[i] = rpc.BatchElem{Method: "eth_getBlockByNumber", Args: []interface{}{toBlockNumArg(height), false}, Result: &headers[i]}
 	}
 
-	ctxwt, cancel := context.WithTimeout(context.Background(), defaultRequestTimeout)
-	defer cancel()
-	err := c.rpc.BatchCallContext(ctxwt, batchElems)
+	_, err := retry.Do(context.Background(), defaultRequestAttempts, c.bOff, func() (struct{}, error) {
+		ctxwt, cancel := context.WithTimeout(context.Background(), defaultRequestTimeout)
+		defer cancel()
+		return struct{}{}, c.rpc.BatchCallContext(ctxwt, batchElems)
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -144,15 +217,15 @@ func (c *clnt) BlockHeadersByRange(startHeight, endHeight *big.Int) ([]types.Hea
 	// Parse the headers.
 	//  - Ensure integrity that they build on top of each other
 	//  - Truncate out headers that do not exist (endHeight > "latest")
-	size := 0
+	result := make([]types.Header, 0, count)
 	for i, batchElem := range batchElems {
 		if batchElem.Error != nil {
-			if size == 0 {
+			if len(result) == 0 {
 				return nil, batchElem.Error
 			} else {
 				break // try return whatever headers are available
 			}
-		} else if batchElem.Result == nil {
+		} else if headers[i] == nil {
 			break
 		}
 
@@ -160,11 +233,10 @@ func (c *clnt) BlockHeadersByRange(startHeight, endHeight *big.Int) ([]types.Hea
 			return nil, fmt.Errorf("queried header %s does not follow parent %s", headers[i].Hash(), headers[i-1].Hash())
 		}
 
-		size = size + 1
+		result = append(result, *headers[i])
 	}
 
-	headers = headers[:size]
-	return headers, nil
+	return result, nil
 }
 
 func (c *clnt) TxByHash(hash common.Hash) (*types.Transaction, error) {
@@ -182,18 +254,58 @@ func (c *clnt) TxByHash(hash common.Hash) (*types.Transaction, error) {
 	return tx, nil
 }
 
-// StorageHash returns the sha3 of the storage root for the specified account
-func (c *clnt) StorageHash(address common.Address, blockNumber *big.Int) (common.Hash, error) {
+// StorageProof returns the account and storage proofs for the specified account and storage keys at the
+// block with the supplied hash. Requesting by hash, rather than by height or tag, guarantees the proofs
+// correspond to exactly that block, even if the chain head moves or reorgs while the request is in-flight.
+//
+// The proofs are verified against the state root of the block header, retrieved within the same batch
+// request, such that the storage hash of the account is never used if inconsistent with the header
+func (c *clnt) StorageProof(address common.Address, keys []common.Hash, blockHash common.Hash) (*eth.AccountResult, error) {
+	var header *types.Header
+	var proof *eth.AccountResult
+
+	batchElems := make([]rpc.BatchElem, 2)
+	batchElems[0] = rpc.BatchElem{Method: "eth_getBlockByHash", Args: []interface{}{blockHash, false}, Result: &header}
+	batchElems[1] = rpc.BatchElem{Method: "eth_getProof", Args: []interface{}{address, keys, rpc.BlockNumberOrHashWithHash(blockHash, false)}, Result: &proof}
+
 	ctxwt, cancel := context.WithTimeout(context.Background(), defaultRequestTimeout)
 	defer cancel()
-
-	proof := struct{ StorageHash common.Hash }{}
-	err := c.rpc.CallContext(ctxwt, &proof, "eth_getProof", address, nil, toBlockNumArg(blockNumber))
+	err := c.rpc.BatchCallContext(ctxwt, batchElems)
 	if err != nil {
-		return common.Hash{}, err
+		return nil, err
+	}
+
+	if batchElems[0].Error != nil {
+		return nil, fmt.Errorf("unable to query header %s: %w", blockHash, batchElems[0].Error)
+	} else if header == nil {
+		return nil, ethereum.NotFound
+	}
+	if batchElems[1].Error != nil {
+		return nil, fmt.Errorf("unable to query proof: %w", batchElems[1].Error)
+	} else if proof == nil {
+		return nil, ethereum.NotFound
 	}
 
-	return proof.StorageHash, nil
+	// sanity check on the data returned
+	if header.Hash() != blockHash {
+		return nil, errors.New("header mismatch")
+	}
+	if proof.Address != address {
+		return nil, fmt.Errorf("proof address %s does not match requested address %s", proof.Address, address)
+	}
+	if len(proof.StorageProof) != len(keys) {
+		return nil, fmt.Errorf("missing storage proof data, got %d proof entries but requested %d storage keys", len(proof.StorageProof), len(keys))
+	}
+	for i, key := range keys {
+		if proof.StorageProof[i].Key != key {
+			return nil, fmt.Errorf("unexpected storage proof key difference for entry %d: got %s but requested %s", i, proof.StorageProof[i].Key, key)
+		}
+	}
+	if err := proof.Verify(header.Root); err != nil {
+		return nil, fmt.Errorf("proof is inconsistent with state root %s of block %s: %w", header.Root, blockHash, err)
+	}
+
+	return proof, nil
 }
 
 func (c *clnt) Close() {
@@ -239,6 +351,160 @@ func (c *clnt) FilterLogs(query ethereum.FilterQuery) (Logs, error) {
 	return Logs{Logs: logs, ToBlockHeader: &header}, nil
 }
 
+// FilteredReceipts returns the receipts of transactions within the specified block range -- inclusive -- which
+// emitted at least one log matching the supplied addresses and topics. The matching logs are located with
+// `eth_getLogs` when the provider permits it, falling back to per-block `eth_getBlockReceipts` requests only
+// when the provider rejects the query due to restrictive range or result limits. Other errors are returned
+func (c *clnt) FilteredReceipts(startHeight, endHeight *big.Int, addresses []common.Address, topics [][]common.Hash) ([]*types.Receipt, error) {
+	if startHeight == nil || endHeight == nil {
+		return nil, errors.New("start and end heights must be specified")
+	} else if startHeight.Cmp(endHeight) > 0 {
+		return nil, fmt.Errorf("start height %s is after end height %s", startHeight, endHeight)
+	}
+
+	query := ethereum.FilterQuery{FromBlock: startHeight, ToBlock: endHeight, Addresses: addresses, Topics: topics}
+	arg, err := toFilterArg(query)
+	if err != nil {
+		return nil, err
+	}
+
+	var logs []types.Log
+	ctxwt, cancel := context.WithTimeout(context.Background(), defaultRequestTimeout)
+	defer cancel()
+	if err := c.rpc.CallContext(ctxwt, &logs, "eth_getLogs", arg); err != nil {
+		if isLogsLimitError(err) {
+			return c.filteredBlockReceipts(startHeight, endHeight, addresses, topics)
+		}
+		return nil, fmt.Errorf("unable to query logs: %w", err)
+	}
+
+	var txHashes []common.Hash
+	seen := make(map[common.Hash]bool)
+	for _, log := range logs {
+		if !seen[log.TxHash] {
+			seen[log.TxHash] = true
+			txHashes = append(txHashes, log.TxHash)
+		}
+	}
+
+	receipts := make([]*types.Receipt, 0, len(txHashes))
+	for len(txHashes) > 0 {
+		size := min(len(txHashes), defaultReceiptBatchSize)
+		batch := make([]*types.Receipt, size)
+		batchElems := make([]rpc.BatchElem, size)
+		for i, txHash := range txHashes[:size] {
+			batchElems[i] = rpc.BatchElem{Method: "eth_getTransactionReceipt", Args: []interface{}{txHash}, Result: &batch[i]}
+		}
+
+		ctxwt, cancel := context.WithTimeout(context.Background(), defaultRequestTimeout)
+		err := c.rpc.BatchCallContext(ctxwt, batchElems)
+		cancel()
+		if err != nil {
+			return nil, err
+		}
+
+		for i, batchElem := range batchElems {
+			if batchElem.Error != nil {
+				return nil, fmt.Errorf("unable to query receipt for tx %s: %w", txHashes[i], batchElem.Error)
+			} else if batch[i] == nil {
+				return nil, fmt.Errorf("receipt for tx %s not found: %w", txHashes[i], ethereum.NotFound)
+			}
+		}
+
+		receipts = append(receipts, batch...)
+		txHashes = txHashes[size:]
+	}
+
+	return receipts, nil
+}
+
+// filteredBlockReceipts retrieves all receipts within the specified block range -- inclusive -- via
+// `eth_getBlockReceipts`, retaining those with at least one log matching the supplied addresses and topics
+func (c *clnt) filteredBlockReceipts(startHeight, endHeight *big.Int, addresses []common.Address, topics [][]common.Hash) ([]*types.Receipt, error) {
+	count := new(big.Int).Sub(endHeight, startHeight).Uint64() + 1
+
+	var receipts []*types.Receipt
+	for offset := uint64(0); offset < count; offset += defaultReceiptBatchSize {
+		batchCount := min(count-offset, defaultReceiptBatchSize)
+		blockReceipts := make([][]*types.Receipt, batchCount)
+		batchElems := make([]rpc.BatchElem, batchCount)
+		for i := uint64(0); i < batchCount; i++ {
+			height := new(big.Int).Add(startHeight, new(big.Int).SetUint64(offset+i))
+			batchElems[i] = rpc.BatchElem{Method: "eth_getBlockReceipts", Args: []interface{}{toBlockNumArg(height)}, Result: &blockReceipts[i]}
+		}
+
+		ctxwt, cancel := context.WithTimeout(context.Background(), defaultRequestTimeout)
+		err := c.rpc.BatchCallContext(ctxwt, batchElems)
+		cancel()
+		if err != nil {
+			return nil, err
+		}
+
+		for i, batchElem := range batchElems {
+			if batchElem.Error != nil {
+				return nil, fmt.Errorf("unable to query receipts for block %s: %w", batchElem.Args[0], batchElem.Error)
+			} else if blockReceipts[i] == nil {
+				return nil, fmt.Errorf("receipts for block %s not found: %w", batchElem.Args[0], ethereum.NotFound)
+			}
+			for _, receipt := range blockReceipts[i] {
+				for _, log := range receipt.Logs {
+					if logMatches(log, addresses, topics) {
+						receipts = append(receipts, receipt)
+						break
+					}
+				}
+			}
+		}
+	}
+
+	return receipts, nil
+}
+
+// logsLimitErrorMessages are fragments of the error messages providers respond to
+// `eth_getLogs` with when a query exceeds their range or result-size limits
+var logsLimitErrorMessages = []string{
+	"block range",
+	"range too large",
+	"range is too large",
+	"query returned more than",
+	"response size exceeded",
+}
+
+// isLogsLimitError returns true if the error is the provider rejecting an `eth_getLogs`
+// query due to range or result-size limits, rather than a transport or server failure.
+// The error code alone is not considered, as providers also use `rpcLimitExceededCode`
+// when throttling requests, which the per-block fallback would only make worse
+func isLogsLimitError(err error) bool {
+	var rpcErr rpc.Error
+	if !errors.As(err, &rpcErr) {
+		return false
+	}
+
+	msg := strings.ToLower(rpcErr.Error())
+	for _, fragment := range logsLimitErrorMessages {
+		if strings.Contains(msg, fragment) {
+			return true
+		}
+	}
+	return false
+}
+
+// logMatches applies the `eth_getLogs` address and topic filtering semantics to a single log
+func logMatches(log *types.Log, addresses []common.Address, topics [][]common.Hash) bool {
+	if len(addresses) > 0 && !slices.Contains(addresses, log.Address) {
+		return false
+	}
+	if len(topics) > len(log.Topics) {
+		return false
+	}
+	for i, sub := range topics {
+		if len(sub) > 0 && !slices.Contains(sub, log.Topics[i]) {
+			return false
+		}
+	}
+	return true
+}
+
 // Modeled off op-service/client.go. We can refactor this once the client/metrics portion
 // of op-service/client has been generalized
 
@@ -251,17 +517,63 @@ type RPC interface {
 type rpcClient struct {
 	rpc     *rpc.Client
 	metrics Metricer
+
+	// in-flight requests, keyed by method and arguments
+	requests singleflight.Group
 }
 
 func NewRPC(client *rpc.Client, metrics Metricer) RPC {
-	return &rpcClient{client, metrics}
+	return &rpcClient{rpc: client, metrics: metrics}
 }
 
 func (c *rpcClient) Close() {
 	c.rpc.Close()
 }
 
+// CallContext performs the request, sharing the response of an identical request that is
+// already in-flight rather than issuing a duplicate request to the backend. The shared request
+// always runs with `defaultRequestTimeout`, regardless of any deadline set by the caller, while
+// the caller's context only bounds how long it waits for the response
 func (c *rpcClient) CallContext(ctx context.Context, result any, method string, args ...any) error {
+	key, err := json.Marshal(append([]any{method}, args...))
+	if err != nil {
+		return c.callContext(ctx, result, method, args...)
+	}
+
+	// The shared request is detached from the context of the caller that initiated it, such
+	// that a cancelled caller does not fail the others. Each caller instead stops waiting on
+	// its own context
+	performed := false
+	ch := c.requests.DoChan(string(key), func() (interface{}, error) {
+		performed = true
+		ctxwt, cancel := context.WithTimeout(context.Background(), defaultRequestTimeout)
+		defer cancel()
+
+		var raw json.RawMessage
+		err := c.callContext(ctxwt, &raw, method, args...)
+		return raw, err
+	})
+
+	var res singleflight.Result
+	select {
+	case res = <-ch:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	if !performed {
+		c.metrics.RecordRPCClientDeduplicatedRequest(method)
+	}
+	if res.Err != nil {
+		return res.Err
+	} else if result == nil {
+		return nil
+	}
+
+	return json.Unmarshal(res.Val.(json.RawMessage), result)
+}
+
+func (c *rpcClient) callContext(ctx context.Context, result any, method string, args ...any) error {
 	record := c.metrics.RecordRPCClientRequest(method)
 	err := c.rpc.CallContext(ctx, result, method, args...)
 	record(err)
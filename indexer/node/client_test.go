@@ -2,11 +2,27 @@ package node
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"math/big"
 	"net"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
+	"github.com/ethereum-optimism/optimism/op-service/eth"
+	"github.com/ethereum-optimism/optimism/op-service/retry"
+	"github.com/ethereum-optimism/optimism/op-service/testutils"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/stretchr/testify/require"
 )
 
@@ -33,3 +49,331 @@ func TestDialEthClientUnavailable(t *testing.T) {
 	_, err = DialEthClient(context.Background(), "mailto://example.com", metrics)
 	require.Error(t, err)
 }
+
+// mockBatchRPC serves `eth_getBlockByNumber`, `eth_getBlockReceipts` and `eth_getTransactionReceipt`
+// batch calls, failing the first `failures` batch calls. Headers past the last one are served as not
+// found, either as a null result or, with `notFoundErr`, as an element error. Block receipts past the
+// last block are served as null. `eth_getLogs` calls are served `logs`, or rejected with `logsErr`
+type mockBatchRPC struct {
+	headers     []types.Header
+	receipts    [][]*types.Receipt
+	logs        []types.Log
+	logsErr     error
+	failures    int
+	notFoundErr bool
+	batchSizes  []int
+}
+
+// countingStrategy retries without delay, counting the retries
+type countingStrategy struct {
+	retries int
+}
+
+func (s *countingStrategy) Duration(_ int) time.Duration {
+	s.retries++
+	return 0
+}
+
+func (m *mockBatchRPC) Close() {}
+
+func (m *mockBatchRPC) CallContext(_ context.Context, result any, method string, _ ...any) error {
+	if method != "eth_getLogs" {
+		return errors.New("not implemented")
+	} else if m.logsErr != nil {
+		return m.logsErr
+	}
+	*result.(*[]types.Log) = m.logs
+	return nil
+}
+
+func (m *mockBatchRPC) BatchCallContext(_ context.Context, b []rpc.BatchElem) error {
+	if m.failures > 0 {
+		m.failures--
+		return errors.New("transient failure")
+	}
+
+	m.batchSizes = append(m.batchSizes, len(b))
+	for i, elem := range b {
+		switch elem.Method {
+		case "eth_getBlockByNumber":
+			height, err := hexutil.DecodeUint64(elem.Args[0].(string))
+			if err != nil {
+				return err
+			}
+			if height < uint64(len(m.headers)) {
+				*elem.Result.(**types.Header) = &m.headers[height]
+			} else if m.notFoundErr {
+				b[i].Error = errors.New("header not found")
+			}
+		case "eth_getBlockReceipts":
+			height, err := hexutil.DecodeUint64(elem.Args[0].(string))
+			if err != nil {
+				return err
+			}
+			if height < uint64(len(m.receipts)) {
+				*elem.Result.(*[]*types.Receipt) = m.receipts[height]
+			}
+		case "eth_getTransactionReceipt":
+			txHash := elem.Args[0].(common.Hash)
+			for _, blockReceipts := range m.receipts {
+				for _, receipt := range blockReceipts {
+					if receipt.TxHash == txHash {
+						*elem.Result.(**types.Receipt) = receipt
+					}
+				}
+			}
+		default:
+			b[i].Error = errors.New("not implemented")
+		}
+	}
+	return nil
+}
+
+func TestBlockHeadersByRangeBatching(t *testing.T) {
+	headers := make([]types.Header, 250)
+	for i := range headers {
+		headers[i].Number = big.NewInt(int64(i))
+		if i > 0 {
+			headers[i].ParentHash = headers[i-1].Hash()
+		}
+	}
+
+	bOff := &countingStrategy{}
+	mockRPC := &mockBatchRPC{headers: headers, failures: 1}
+	client := &clnt{rpc: mockRPC, bOff: bOff}
+
+	result, err := client.BlockHeadersByRange(big.NewInt(0), big.NewInt(249))
+	require.NoError(t, err)
+	require.Len(t, result, 250)
+	for i := range result {
+		require.Equal(t, headers[i].Hash(), result[i].Hash())
+	}
+
+	// batches are bounded and the failed batch is retried
+	require.Equal(t, []int{100, 100, 50}, mockRPC.batchSizes)
+	require.Equal(t, 1, bOff.retries)
+
+	// persistent failures are surfaced
+	mockRPC.failures = defaultRequestAttempts
+	_, err = client.BlockHeadersByRange(big.NewInt(0), big.NewInt(9))
+	require.Error(t, err)
+
+	// ranges past the head are truncated without retrying, whether headers are null or errors
+	for _, notFoundErr := range []bool{false, true} {
+		bOff.retries = 0
+		mockRPC.notFoundErr = notFoundErr
+		result, err = client.BlockHeadersByRange(big.NewInt(150), big.NewInt(399))
+		require.NoError(t, err)
+		require.Len(t, result, 100)
+		require.Equal(t, headers[249].Hash(), result[99].Hash())
+		require.Zero(t, bOff.retries)
+	}
+}
+
+func TestFilteredReceipts(t *testing.T) {
+	// one receipt per block, each with two matching logs
+	receipts := make([][]*types.Receipt, defaultReceiptBatchSize+50)
+	logs := make([]types.Log, 0, 2*len(receipts))
+	for i := range receipts {
+		txHash := common.BigToHash(big.NewInt(int64(i)))
+		receipts[i] = []*types.Receipt{{TxHash: txHash}}
+		logs = append(logs, types.Log{TxHash: txHash}, types.Log{TxHash: txHash})
+	}
+
+	mockRPC := &mockBatchRPC{receipts: receipts, logs: logs}
+	client := &clnt{rpc: mockRPC, bOff: retry.Fixed(0)}
+
+	result, err := client.FilteredReceipts(big.NewInt(0), big.NewInt(int64(len(receipts)-1)), nil, nil)
+	require.NoError(t, err)
+	require.Len(t, result, len(receipts))
+	for i := range result {
+		require.Equal(t, receipts[i][0].TxHash, result[i].TxHash)
+	}
+
+	// receipts are requested once per transaction, in bounded batches
+	require.Equal(t, []int{defaultReceiptBatchSize, 50}, mockRPC.batchSizes)
+
+	// missing receipts are surfaced
+	mockRPC.logs = append(mockRPC.logs, types.Log{TxHash: common.Hash{0xff}})
+	_, err = client.FilteredReceipts(big.NewInt(0), big.NewInt(int64(len(receipts)-1)), nil, nil)
+	require.ErrorIs(t, err, ethereum.NotFound)
+
+	// errors other than provider limits are returned without falling back to block receipts
+	mockRPC.logsErr = errors.New("connection refused")
+	mockRPC.batchSizes = nil
+	_, err = client.FilteredReceipts(big.NewInt(0), big.NewInt(1), nil, nil)
+	require.ErrorContains(t, err, "connection refused")
+	require.Empty(t, mockRPC.batchSizes)
+
+	// heights are required
+	_, err = client.FilteredReceipts(nil, big.NewInt(1), nil, nil)
+	require.Error(t, err)
+	_, err = client.FilteredReceipts(big.NewInt(1), nil, nil, nil)
+	require.Error(t, err)
+	_, err = client.FilteredReceipts(big.NewInt(2), big.NewInt(1), nil, nil)
+	require.Error(t, err)
+}
+
+// limitError is an `rpc.Error` as returned by providers rejecting `eth_getLogs` queries
+type limitError struct {
+	code int
+	msg  string
+}
+
+func (e *limitError) Error() string  { return e.msg }
+func (e *limitError) ErrorCode() int { return e.code }
+
+func TestFilteredReceiptsFallback(t *testing.T) {
+	contract, topic := common.Address{0x01}, common.Hash{0x02}
+	matching := &types.Receipt{TxHash: common.Hash{0xaa}, Logs: []*types.Log{{Address: contract, Topics: []common.Hash{topic}}}}
+	wrongTopic := &types.Receipt{TxHash: common.Hash{0xbb}, Logs: []*types.Log{{Address: contract, Topics: []common.Hash{{0x03}}}}}
+	wrongAddress := &types.Receipt{TxHash: common.Hash{0xcc}, Logs: []*types.Log{{Address: common.Address{0x04}, Topics: []common.Hash{topic}}}}
+
+	// eth_getLogs is rejected by the mock, requiring per-block receipts
+	mockRPC := &mockBatchRPC{
+		receipts: [][]*types.Receipt{{wrongTopic}, {wrongAddress, matching}, {}},
+		logsErr:  &limitError{code: -32005, msg: "query returned more than 10000 results"},
+	}
+	client := &clnt{rpc: mockRPC, bOff: retry.Fixed(0)}
+
+	receipts, err := client.FilteredReceipts(big.NewInt(0), big.NewInt(2), []common.Address{contract}, [][]common.Hash{{topic}})
+	require.NoError(t, err)
+	require.Len(t, receipts, 1)
+	require.Equal(t, matching.TxHash, receipts[0].TxHash)
+
+	// no filter matches every receipt with logs
+	receipts, err = client.FilteredReceipts(big.NewInt(0), big.NewInt(2), nil, nil)
+	require.NoError(t, err)
+	require.Len(t, receipts, 3)
+
+	// range limits are also recognized by their message
+	mockRPC.logsErr = &limitError{code: -32000, msg: "exceed maximum block range: 1000"}
+	receipts, err = client.FilteredReceipts(big.NewInt(0), big.NewInt(2), nil, nil)
+	require.NoError(t, err)
+	require.Len(t, receipts, 3)
+
+	// blocks without receipts are not silently skipped
+	_, err = client.FilteredReceipts(big.NewInt(0), big.NewInt(3), nil, nil)
+	require.ErrorIs(t, err, ethereum.NotFound)
+
+	// throttling shares the error code, but is returned rather than multiplying the requests
+	mockRPC.logsErr = &limitError{code: -32005, msg: "rate limit exceeded, too many requests"}
+	mockRPC.batchSizes = nil
+	_, err = client.FilteredReceipts(big.NewInt(0), big.NewInt(2), nil, nil)
+	require.ErrorContains(t, err, "rate limit exceeded")
+	require.Empty(t, mockRPC.batchSizes)
+}
+
+type blockingService struct {
+	calls   atomic.Int32
+	called  chan struct{}
+	release chan struct{}
+}
+
+func (s *blockingService) Echo(value string) string {
+	if s.calls.Add(1) == 1 {
+		close(s.called)
+	}
+	<-s.release
+	return value
+}
+
+func TestRPCDeduplicatesInflightRequests(t *testing.T) {
+	service := &blockingService{called: make(chan struct{}), release: make(chan struct{})}
+	server := rpc.NewServer()
+	require.NoError(t, server.RegisterName("test", service))
+	defer server.Stop()
+
+	client := NewRPC(rpc.DialInProc(server), NewMetrics(prometheus.NewRegistry(), "test"))
+	defer client.Close()
+
+	var wg sync.WaitGroup
+	results := make([]string, 5)
+	errs := make([]error, len(results))
+	for i := range results {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			errs[i] = client.CallContext(context.Background(), &results[i], "test_echo", "hello")
+		}(i)
+	}
+
+	// allow the remaining requests to join the in-flight request
+	<-service.called
+	time.Sleep(100 * time.Millisecond)
+
+	// a cancelled caller stops waiting without failing the in-flight request
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	var cancelled string
+	require.ErrorIs(t, client.CallContext(ctx, &cancelled, "test_echo", "hello"), context.Canceled)
+	require.Empty(t, cancelled)
+
+	close(service.release)
+	wg.Wait()
+
+	require.Equal(t, int32(1), service.calls.Load())
+	for i, result := range results {
+		require.NoError(t, errs[i])
+		require.Equal(t, "hello", result)
+	}
+
+	// requests with different arguments are not shared
+	var other string
+	require.NoError(t, client.CallContext(context.Background(), &other, "test_echo", "world"))
+	require.Equal(t, "world", other)
+	require.Equal(t, int32(2), service.calls.Load())
+}
+
+type proofService struct {
+	header *types.Header
+	proof  eth.AccountResult
+}
+
+func (s *proofService) GetBlockByHash(hash common.Hash, _ bool) (*types.Header, error) {
+	if hash != s.header.Hash() {
+		return nil, errors.New("unknown block")
+	}
+	return s.header, nil
+}
+
+func (s *proofService) GetProof(address common.Address, keys []common.Hash, block rpc.BlockNumberOrHash) (*eth.AccountResult, error) {
+	hash, ok := block.Hash()
+	if !ok || hash != s.header.Hash() || address != s.proof.Address {
+		return nil, errors.New("unknown proof")
+	}
+	return &s.proof, nil
+}
+
+func TestStorageProofByBlockHash(t *testing.T) {
+	key := common.Hash{0x01}
+	account, stateRoot := testutils.MakeProvenAccount(t, []common.Hash{key})
+	service := &proofService{header: &types.Header{Number: big.NewInt(1), Difficulty: big.NewInt(0), Root: stateRoot}, proof: *account}
+	server := rpc.NewServer()
+	require.NoError(t, server.RegisterName("eth", service))
+	defer server.Stop()
+
+	client := &clnt{rpc: NewRPC(rpc.DialInProc(server), NewMetrics(prometheus.NewRegistry(), "test"))}
+	defer client.Close()
+
+	proof, err := client.StorageProof(account.Address, []common.Hash{key}, service.header.Hash())
+	require.NoError(t, err)
+	require.Equal(t, account.Address, proof.Address)
+	require.Equal(t, account.StorageHash, proof.StorageHash)
+	require.Equal(t, key, proof.StorageProof[0].Key)
+
+	// unknown block
+	_, err = client.StorageProof(account.Address, []common.Hash{key}, common.Hash{0xbb})
+	require.Error(t, err)
+
+	// storage proofs must match the requested keys
+	_, err = client.StorageProof(account.Address, nil, service.header.Hash())
+	require.ErrorContains(t, err, "missing storage proof data")
+	_, err = client.StorageProof(account.Address, []common.Hash{{0x02}}, service.header.Hash())
+	require.ErrorContains(t, err, "unexpected storage proof key difference")
+
+	// storage hash inconsistent with the header state root
+	service.proof.StorageHash = common.Hash{0xcc}
+	_, err = client.StorageProof(account.Address, []common.Hash{key}, service.header.Hash())
+	require.ErrorContains(t, err, "inconsistent with state root")
+}
@@ -1,8 +1,13 @@
 package node
 
 import (
+	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"net"
+	"net/http"
+	"syscall"
 
 	"github.com/ethereum-optimism/optimism/op-service/metrics"
 	"github.com/ethereum/go-ethereum"
@@ -14,17 +19,43 @@ import (
 var (
 	MetricsNamespace = "op_indexer_rpc"
 	batchMethod      = "<batch>"
+	defaultEndpoint  = "<default>"
+)
+
+// RPC client error types, distinguishing provider throttling from outages
+const (
+	errTypeTimeout           = "timeout"
+	errTypeRateLimited       = "rate_limited"
+	errTypeConnectionRefused = "connection_refused"
+	errTypeInvalidResponse   = "invalid_response"
+	errTypeNotFound          = "not_found"
+	errTypeRPC               = "rpc_error"
+	errTypeHTTP              = "http_error"
+	errTypeUnknown           = "unknown"
+
+	// rpcLimitExceededCode is the JSON-RPC error code commonly returned by
+	// providers when a request is throttled (EIP-1474)
+	rpcLimitExceededCode = -32005
 )
 
 type Metricer interface {
 	RecordRPCClientRequest(method string) func(err error)
 	RecordRPCClientBatchRequest(b []rpc.BatchElem) func(err error)
+	RecordRPCClientDeduplicatedRequest(method string)
+
+	// WithEndpoint returns a Metricer sharing the same metrics, attributing
+	// errors to the supplied endpoint label
+	WithEndpoint(endpoint string) Metricer
 }
 
 type clientMetrics struct {
-	rpcClientRequestsTotal          *prometheus.CounterVec
-	rpcClientRequestDurationSeconds *prometheus.HistogramVec
-	rpcClientResponsesTotal         *prometheus.CounterVec
+	rpcClientRequestsTotal             *prometheus.CounterVec
+	rpcClientRequestDurationSeconds    *prometheus.HistogramVec
+	rpcClientResponsesTotal            *prometheus.CounterVec
+	rpcClientDeduplicatedRequestsTotal *prometheus.CounterVec
+	rpcClientErrorsTotal               *prometheus.CounterVec
+
+	endpoint string
 }
 
 func NewMetrics(registry *prometheus.Registry, subsystem string) Metricer {
@@ -56,9 +87,34 @@ func NewMetrics(registry *prometheus.Registry, subsystem string) Metricer {
 			"method",
 			"error",
 		}),
+		rpcClientDeduplicatedRequestsTotal: factory.NewCounterVec(prometheus.CounterOpts{
+			Namespace: MetricsNamespace,
+			Subsystem: subsystem,
+			Name:      "deduplicated_requests_total",
+			Help:      "Total RPC requests served by an identical in-flight request of the RPC client",
+		}, []string{
+			"method",
+		}),
+		rpcClientErrorsTotal: factory.NewCounterVec(prometheus.CounterOpts{
+			Namespace: MetricsNamespace,
+			Subsystem: subsystem,
+			Name:      "errors_total",
+			Help:      "Total RPC request failures of the RPC client, classified by type",
+		}, []string{
+			"method",
+			"endpoint",
+			"type",
+		}),
+		endpoint: defaultEndpoint,
 	}
 }
 
+func (m *clientMetrics) WithEndpoint(endpoint string) Metricer {
+	withEndpoint := *m
+	withEndpoint.endpoint = endpoint
+	return &withEndpoint
+}
+
 func (m *clientMetrics) RecordRPCClientRequest(method string) func(err error) {
 	m.rpcClientRequestsTotal.WithLabelValues(method).Inc()
 	timer := prometheus.NewTimer(m.rpcClientRequestDurationSeconds.WithLabelValues(method))
@@ -86,6 +142,10 @@ func (m *clientMetrics) RecordRPCClientBatchRequest(b []rpc.BatchElem) func(err
 	}
 }
 
+func (m *clientMetrics) RecordRPCClientDeduplicatedRequest(method string) {
+	m.rpcClientDeduplicatedRequestsTotal.WithLabelValues(method).Inc()
+}
+
 func (m *clientMetrics) recordRPCClientResponse(method string, err error) {
 	var errStr string
 	var rpcErr rpc.Error
@@ -102,4 +162,36 @@ func (m *clientMetrics) recordRPCClientResponse(method string, err error) {
 		errStr = "<unknown>"
 	}
 	m.rpcClientResponsesTotal.WithLabelValues(method, errStr).Inc()
+
+	if err != nil {
+		m.rpcClientErrorsTotal.WithLabelValues(method, m.endpoint, classifyRPCError(err)).Inc()
+	}
+}
+
+// classifyRPCError maps an RPC client error to one of the error types
+func classifyRPCError(err error) string {
+	var rpcErr rpc.Error
+	var httpErr rpc.HTTPError
+	var netErr net.Error
+	var syntaxErr *json.SyntaxError
+	var unmarshalErr *json.UnmarshalTypeError
+	switch {
+	case errors.Is(err, context.DeadlineExceeded), errors.As(err, &netErr) && netErr.Timeout():
+		return errTypeTimeout
+	case errors.As(err, &httpErr) && httpErr.StatusCode == http.StatusTooManyRequests,
+		errors.As(err, &rpcErr) && rpcErr.ErrorCode() == rpcLimitExceededCode:
+		return errTypeRateLimited
+	case errors.Is(err, syscall.ECONNREFUSED):
+		return errTypeConnectionRefused
+	case errors.As(err, &syntaxErr), errors.As(err, &unmarshalErr), errors.Is(err, rpc.ErrNoResult):
+		return errTypeInvalidResponse
+	case errors.Is(err, ethereum.NotFound):
+		return errTypeNotFound
+	case errors.As(err, &rpcErr):
+		return errTypeRPC
+	case errors.As(err, &httpErr):
+		return errTypeHTTP
+	default:
+		return errTypeUnknown
+	}
 }
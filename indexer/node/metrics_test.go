@@ -0,0 +1,60 @@
+package node
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"syscall"
+	"testing"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/rpc"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+type testRPCError struct{ code int }
+
+func (e testRPCError) Error() string  { return fmt.Sprintf("rpc error %d", e.code) }
+func (e testRPCError) ErrorCode() int { return e.code }
+
+func TestClassifyRPCError(t *testing.T) {
+	tests := []struct {
+		err      error
+		expected string
+	}{
+		{context.DeadlineExceeded, errTypeTimeout},
+		{fmt.Errorf("wrapped: %w", context.DeadlineExceeded), errTypeTimeout},
+		{rpc.HTTPError{StatusCode: http.StatusTooManyRequests}, errTypeRateLimited},
+		{testRPCError{code: rpcLimitExceededCode}, errTypeRateLimited},
+		{fmt.Errorf("dial: %w", syscall.ECONNREFUSED), errTypeConnectionRefused},
+		{&json.SyntaxError{}, errTypeInvalidResponse},
+		{rpc.ErrNoResult, errTypeInvalidResponse},
+		{ethereum.NotFound, errTypeNotFound},
+		{testRPCError{code: -32000}, errTypeRPC},
+		{rpc.HTTPError{StatusCode: http.StatusBadGateway}, errTypeHTTP},
+		{errors.New("boom"), errTypeUnknown},
+	}
+
+	for _, test := range tests {
+		require.Equal(t, test.expected, classifyRPCError(test.err), test.err.Error())
+	}
+}
+
+func TestRecordRPCClientErrorsPerEndpoint(t *testing.T) {
+	m := NewMetrics(prometheus.NewRegistry(), "test")
+	a, b := m.WithEndpoint("a:8545"), m.WithEndpoint("b:8545")
+
+	a.RecordRPCClientRequest("eth_getLogs")(rpc.HTTPError{StatusCode: http.StatusTooManyRequests})
+	b.RecordRPCClientRequest("eth_getLogs")(context.DeadlineExceeded)
+	b.RecordRPCClientRequest("eth_getLogs")(nil)
+
+	errorsTotal := m.(*clientMetrics).rpcClientErrorsTotal
+	require.Equal(t, 1.0, testutil.ToFloat64(errorsTotal.WithLabelValues("eth_getLogs", "a:8545", errTypeRateLimited)))
+	require.Equal(t, 1.0, testutil.ToFloat64(errorsTotal.WithLabelValues("eth_getLogs", "b:8545", errTypeTimeout)))
+	require.Equal(t, 2, testutil.CollectAndCount(errorsTotal))
+}
@@ -3,6 +3,8 @@ package node
 import (
 	"math/big"
 
+	"github.com/ethereum-optimism/optimism/op-service/eth"
+
 	"github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
@@ -36,9 +38,9 @@ func (m *MockEthClient) TxByHash(hash common.Hash) (*types.Transaction, error) {
 	return args.Get(0).(*types.Transaction), args.Error(1)
 }
 
-func (m *MockEthClient) StorageHash(address common.Address, blockNumber *big.Int) (common.Hash, error) {
-	args := m.Called(address, blockNumber)
-	return args.Get(0).(common.Hash), args.Error(1)
+func (m *MockEthClient) StorageProof(address common.Address, keys []common.Hash, blockHash common.Hash) (*eth.AccountResult, error) {
+	args := m.Called(address, keys, blockHash)
+	return args.Get(0).(*eth.AccountResult), args.Error(1)
 }
 
 func (m *MockEthClient) FilterLogs(query ethereum.FilterQuery) (Logs, error) {
@@ -46,5 +48,10 @@ func (m *MockEthClient) FilterLogs(query ethereum.FilterQuery) (Logs, error) {
 	return args.Get(0).(Logs), args.Error(1)
 }
 
+func (m *MockEthClient) FilteredReceipts(from, to *big.Int, addresses []common.Address, topics [][]common.Hash) ([]*types.Receipt, error) {
+	args := m.Called(from, to, addresses, topics)
+	return args.Get(0).([]*types.Receipt), args.Error(1)
+}
+
 func (m *MockEthClient) Close() {
 }
@@ -124,6 +124,9 @@ type DeployConfig struct {
 	// L2GenesisInteropTimeOffset is the number of seconds after genesis block that the Interop hard fork activates.
 	// Set it to 0 to activate at genesis. Nil to disable Interop.
 	L2GenesisInteropTimeOffset *hexutil.Uint64 `json:"l2GenesisInteropTimeOffset,omitempty"`
+	// InteropDependencySet is the set of L2 chain IDs that the chain accepts cross-chain messages from
+	// once the Interop hard fork activates. Requires L2GenesisInteropTimeOffset to be set.
+	InteropDependencySet []uint64 `json:"interopDependencySet,omitempty"`
 	// L2GenesisBlockExtraData is configurable extradata. Will default to []byte("BEDROCK") if left unspecified.
 	L2GenesisBlockExtraData []byte `json:"l2GenesisBlockExtraData"`
 	// ProxyAdminOwner represents the owner of the ProxyAdmin predeploy on L2.
@@ -350,6 +353,9 @@ func (d *DeployConfig) Check() error {
 	if d.L2GenesisBlockBaseFeePerGas == nil {
 		return fmt.Errorf("%w: L2 genesis block base fee per gas cannot be nil", ErrInvalidDeployConfig)
 	}
+	if len(d.InteropDependencySet) > 0 && d.L2GenesisInteropTimeOffset == nil {
+		return fmt.Errorf("%w: InteropDependencySet cannot be set if Interop is not activated", ErrInvalidDeployConfig)
+	}
 	if d.EnableGovernance {
 		if d.GovernanceTokenName == "" {
 			return fmt.Errorf("%w: GovernanceToken.name cannot be empty", ErrInvalidDeployConfig)
@@ -522,6 +528,18 @@ func (d *DeployConfig) InteropTime(genesisTime uint64) *uint64 {
 	return &v
 }
 
+// InteropDependencyChainIDs returns the chain IDs of the Interop dependency set, or nil if there are none.
+func (d *DeployConfig) InteropDependencyChainIDs() []*big.Int {
+	if len(d.InteropDependencySet) == 0 {
+		return nil
+	}
+	chainIDs := make([]*big.Int, len(d.InteropDependencySet))
+	for i, chainID := range d.InteropDependencySet {
+		chainIDs[i] = new(big.Int).SetUint64(chainID)
+	}
+	return chainIDs
+}
+
 // RollupConfig converts a DeployConfig to a rollup.Config
 func (d *DeployConfig) RollupConfig(l1StartBlock *types.Block, l2GenesisBlockHash common.Hash, l2GenesisBlockNumber uint64) (*rollup.Config, error) {
 	if d.OptimismPortalProxy == (common.Address{}) {
@@ -564,6 +582,7 @@ func (d *DeployConfig) RollupConfig(l1StartBlock *types.Block, l2GenesisBlockHas
 		EclipseTime:            d.EclipseTime(l1StartBlock.Time()),
 		FjordTime:              d.FjordTime(l1StartBlock.Time()),
 		InteropTime:            d.InteropTime(l1StartBlock.Time()),
+		InteropDependencySet:   d.InteropDependencyChainIDs(),
 	}, nil
 }
 
@@ -77,6 +77,24 @@ func TestCopy(t *testing.T) {
 	require.NotEqual(t, decoded, cpy)
 }
 
+// TestInteropDependencySetRequiresInterop ensures that a dependency set cannot be configured without Interop.
+func TestInteropDependencySetRequiresInterop(t *testing.T) {
+	b, err := os.ReadFile("testdata/test-deploy-config-full.json")
+	require.NoError(t, err)
+
+	config := new(DeployConfig)
+	require.NoError(t, json.NewDecoder(bytes.NewReader(b)).Decode(config))
+	config.InteropDependencySet = []uint64{10, 11}
+	config.L2GenesisInteropTimeOffset = nil
+	err = config.Check()
+	require.ErrorIs(t, err, ErrInvalidDeployConfig)
+	require.ErrorContains(t, err, "InteropDependencySet")
+
+	offset := hexutil.Uint64(0)
+	config.L2GenesisInteropTimeOffset = &offset
+	require.NoError(t, config.Check())
+}
+
 // TestL1Deployments ensures that NewL1Deployments can read a JSON file
 // from disk and deserialize all of the key/value pairs correctly.
 func TestL1Deployments(t *testing.T) {
@@ -0,0 +1,72 @@
+package wait
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// LogFilterer is a subset of the [ethclient.Client] interface
+// encompassing methods required to poll for logs.
+type LogFilterer interface {
+	FilterLogs(ctx context.Context, q ethereum.FilterQuery) ([]types.Log, error)
+	BlockNumber(ctx context.Context) (uint64, error)
+}
+
+// ForLog polls until a log matching query is found and returns it decoded with decode.
+// The Parse<Event> methods of the abigen bindings can be passed directly as decode.
+//
+// The query's FromBlock and ToBlock bound the search window. A nil FromBlock starts at genesis and a nil
+// ToBlock follows the chain head, only querying blocks that have not yet been searched on each poll.
+// If ToBlock is set and the head has passed it without a matching log, an error is returned.
+func ForLog[T any](ctx context.Context, client LogFilterer, query ethereum.FilterQuery, decode func(types.Log) (T, error)) (T, error) {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Minute)
+	defer cancel()
+
+	var result T
+	from := new(big.Int)
+	if query.FromBlock != nil {
+		from.Set(query.FromBlock) // Don't clobber caller owned query
+	}
+	err := For(ctx, 100*time.Millisecond, func() (bool, error) {
+		q := query
+		if q.BlockHash == nil {
+			head, err := client.BlockNumber(ctx)
+			if err != nil {
+				return false, fmt.Errorf("get head block number: %w", err)
+			}
+			to := new(big.Int).SetUint64(head)
+			if query.ToBlock != nil && query.ToBlock.Cmp(to) < 0 {
+				to.Set(query.ToBlock)
+			}
+			if from.Cmp(to) > 0 {
+				if query.ToBlock != nil && from.Cmp(query.ToBlock) > 0 {
+					return false, fmt.Errorf("no matching log found up to block %v", query.ToBlock)
+				}
+				return false, nil
+			}
+			q.FromBlock, q.ToBlock = new(big.Int).Set(from), to
+		}
+
+		logs, err := client.FilterLogs(ctx, q)
+		if err != nil {
+			return false, fmt.Errorf("filter logs: %w", err)
+		}
+		if len(logs) == 0 {
+			if q.BlockHash == nil {
+				from.Add(q.ToBlock, big.NewInt(1))
+			}
+			return false, nil
+		}
+		result, err = decode(logs[0])
+		if err != nil {
+			return false, fmt.Errorf("decode log %d in tx %s: %w", logs[0].Index, logs[0].TxHash, err)
+		}
+		return true, nil
+	})
+	return result, err
+}
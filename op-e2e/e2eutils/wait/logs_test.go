@@ -0,0 +1,114 @@
+package wait
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeLogFilterer serves logs up to the current head, advancing through heads on each poll.
+// Polls are counted by BlockNumber calls, or by FilterLogs calls when querying by block hash.
+type fakeLogFilterer struct {
+	heads   []uint64
+	logs    []types.Log
+	polls   int
+	queries []ethereum.FilterQuery
+}
+
+func (f *fakeLogFilterer) head() uint64 {
+	return f.heads[min(f.polls-1, len(f.heads)-1)]
+}
+
+func (f *fakeLogFilterer) BlockNumber(_ context.Context) (uint64, error) {
+	f.polls++
+	return f.head(), nil
+}
+
+func (f *fakeLogFilterer) FilterLogs(_ context.Context, q ethereum.FilterQuery) ([]types.Log, error) {
+	if q.BlockHash != nil {
+		f.polls++
+	}
+	head := f.head()
+	f.queries = append(f.queries, q)
+	var logs []types.Log
+	for _, log := range f.logs {
+		if log.BlockNumber > head {
+			continue
+		}
+		if q.BlockHash != nil {
+			if log.BlockHash == *q.BlockHash {
+				logs = append(logs, log)
+			}
+		} else if log.BlockNumber >= q.FromBlock.Uint64() && log.BlockNumber <= q.ToBlock.Uint64() {
+			logs = append(logs, log)
+		}
+	}
+	return logs, nil
+}
+
+func decodeBlockNumber(log types.Log) (uint64, error) {
+	return log.BlockNumber, nil
+}
+
+func requireWindows(t *testing.T, queries []ethereum.FilterQuery, windows ...[2]uint64) {
+	require.Len(t, queries, len(windows))
+	for i, window := range windows {
+		require.Equal(t, window[0], queries[i].FromBlock.Uint64(), "from block of query %d", i)
+		require.Equal(t, window[1], queries[i].ToBlock.Uint64(), "to block of query %d", i)
+	}
+}
+
+func TestForLog(t *testing.T) {
+	t.Run("AdvancesWindowAfterEmptyScan", func(t *testing.T) {
+		client := &fakeLogFilterer{heads: []uint64{5, 8}, logs: []types.Log{{BlockNumber: 7}}}
+		query := ethereum.FilterQuery{FromBlock: big.NewInt(2)}
+		block, err := ForLog(context.Background(), client, query, decodeBlockNumber)
+		require.NoError(t, err)
+		require.Equal(t, uint64(7), block)
+		requireWindows(t, client.queries, [2]uint64{2, 5}, [2]uint64{6, 8})
+		require.Equal(t, big.NewInt(2), query.FromBlock, "caller owned query is not modified")
+	})
+
+	t.Run("WaitsForNewBlocks", func(t *testing.T) {
+		client := &fakeLogFilterer{heads: []uint64{3, 3, 4}, logs: []types.Log{{BlockNumber: 4}}}
+		block, err := ForLog(context.Background(), client, ethereum.FilterQuery{}, decodeBlockNumber)
+		require.NoError(t, err)
+		require.Equal(t, uint64(4), block)
+		requireWindows(t, client.queries, [2]uint64{0, 3}, [2]uint64{4, 4})
+	})
+
+	t.Run("ErrorsOncePastToBlock", func(t *testing.T) {
+		client := &fakeLogFilterer{heads: []uint64{10}, logs: []types.Log{{BlockNumber: 5}}}
+		_, err := ForLog(context.Background(), client, ethereum.FilterQuery{ToBlock: big.NewInt(3)}, decodeBlockNumber)
+		require.ErrorContains(t, err, "no matching log found up to block 3")
+		requireWindows(t, client.queries, [2]uint64{0, 3})
+	})
+
+	t.Run("BlockHash", func(t *testing.T) {
+		blockHash := common.Hash{0xaa}
+		client := &fakeLogFilterer{heads: []uint64{0, 1}, logs: []types.Log{{BlockNumber: 1, BlockHash: blockHash}}}
+		block, err := ForLog(context.Background(), client, ethereum.FilterQuery{BlockHash: &blockHash}, decodeBlockNumber)
+		require.NoError(t, err)
+		require.Equal(t, uint64(1), block)
+		require.Len(t, client.queries, 2)
+		for _, q := range client.queries {
+			require.Equal(t, &blockHash, q.BlockHash)
+			require.Nil(t, q.FromBlock)
+			require.Nil(t, q.ToBlock)
+		}
+	})
+
+	t.Run("DecodeError", func(t *testing.T) {
+		client := &fakeLogFilterer{heads: []uint64{1}, logs: []types.Log{{BlockNumber: 1}}}
+		_, err := ForLog(context.Background(), client, ethereum.FilterQuery{}, func(types.Log) (uint64, error) {
+			return 0, errors.New("boom")
+		})
+		require.ErrorContains(t, err, "boom")
+	})
+}
@@ -2,12 +2,15 @@ package wait
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"math/big"
+	"strings"
 	"time"
 
 	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/core/types"
@@ -30,15 +33,18 @@ func ForBalanceChange(ctx context.Context, client *ethclient.Client, address com
 	)
 }
 
-func ForReceiptOK(ctx context.Context, client *ethclient.Client, hash common.Hash) (*types.Receipt, error) {
-	return ForReceipt(ctx, client, hash, types.ReceiptStatusSuccessful)
+// ForReceiptOK waits for the receipt of the transaction and requires it to be successful.
+// If the transaction reverted, the revert reason is included in the returned error. Custom errors are decoded
+// using the supplied contract ABIs, while Error(string) and Panic(uint256) reverts are always decoded.
+func ForReceiptOK(ctx context.Context, client *ethclient.Client, hash common.Hash, errABIs ...*abi.ABI) (*types.Receipt, error) {
+	return ForReceipt(ctx, client, hash, types.ReceiptStatusSuccessful, errABIs...)
 }
 
 func ForReceiptFail(ctx context.Context, client *ethclient.Client, hash common.Hash) (*types.Receipt, error) {
 	return ForReceipt(ctx, client, hash, types.ReceiptStatusFailed)
 }
 
-func ForReceipt(ctx context.Context, client *ethclient.Client, hash common.Hash, status uint64) (*types.Receipt, error) {
+func ForReceipt(ctx context.Context, client *ethclient.Client, hash common.Hash, status uint64, errABIs ...*abi.ABI) (*types.Receipt, error) {
 	ctx, cancel := context.WithTimeout(ctx, 2*time.Minute)
 	defer cancel()
 	ticker := time.NewTicker(100 * time.Millisecond)
@@ -57,7 +63,10 @@ func ForReceipt(ctx context.Context, client *ethclient.Client, hash common.Hash,
 			return nil, fmt.Errorf("failed to get receipt: %w", err)
 		}
 		if receipt.Status != status {
-			printDebugTrace(ctx, client, hash)
+			trace := debugTrace(ctx, client, hash)
+			if receipt.Status == types.ReceiptStatusFailed {
+				return receipt, fmt.Errorf("expected status %d, but got %d (revert reason: %s)", status, receipt.Status, revertReason(trace, errABIs))
+			}
 			return receipt, fmt.Errorf("expected status %d, but got %d", status, receipt.Status)
 		}
 		return receipt, nil
@@ -72,8 +81,10 @@ func (s *jsonRawString) UnmarshalJSON(input []byte) error {
 	return nil
 }
 
-// printDebugTrace logs debug_traceTransaction output to aid in debugging unexpected receipt statuses
-func printDebugTrace(ctx context.Context, client *ethclient.Client, txHash common.Hash) {
+// debugTrace fetches the callTracer output of debug_traceTransaction, from which the revert reason of
+// an unexpected receipt status is decoded. The trace is also printed to aid in debugging.
+// An empty string is returned if the trace is unavailable.
+func debugTrace(ctx context.Context, client *ethclient.Client, txHash common.Hash) jsonRawString {
 	var trace jsonRawString
 	options := map[string]any{
 		"enableReturnData": true,
@@ -83,9 +94,67 @@ func printDebugTrace(ctx context.Context, client *ethclient.Client, txHash commo
 	err := client.Client().CallContext(ctx, &trace, "debug_traceTransaction", hexutil.Bytes(txHash.Bytes()), options)
 	if err != nil {
 		fmt.Printf("TxTrace unavailable: %v\n", err)
-		return
+		return ""
 	}
 	fmt.Printf("TxTrace: %v\n", trace)
+	return trace
+}
+
+// revertReason extracts and decodes the revert data of the top-level call from a callTracer trace
+// of a failed transaction. Any failure to do so is described in the returned string instead.
+func revertReason(trace jsonRawString, errABIs []*abi.ABI) string {
+	if trace == "" {
+		return "<unavailable: no trace>"
+	}
+	var call struct {
+		Output hexutil.Bytes `json:"output"`
+		Error  string        `json:"error"`
+	}
+	if err := json.Unmarshal([]byte(trace), &call); err != nil {
+		return fmt.Sprintf("<unavailable: invalid trace: %v>", err)
+	}
+	if len(call.Output) == 0 {
+		if call.Error == "" {
+			return "<unavailable: no revert data>"
+		}
+		return call.Error
+	}
+	return decodeRevertData(call.Output, errABIs)
+}
+
+// decodeRevertData decodes Error(string) and Panic(uint256) reverts, as well as custom errors defined in errABIs.
+// Unknown revert data is returned as hex.
+func decodeRevertData(data []byte, errABIs []*abi.ABI) string {
+	if reason, err := abi.UnpackRevert(data); err == nil {
+		return reason
+	}
+	if len(data) >= 4 {
+		var selector [4]byte
+		copy(selector[:], data[:4])
+		for _, errABI := range errABIs {
+			customErr, err := errABI.ErrorByID(selector)
+			if err != nil {
+				continue
+			}
+			args, err := customErr.Inputs.Unpack(data[4:])
+			if err != nil {
+				return fmt.Sprintf("%s <failed to unpack args: %v>", customErr.Sig, err)
+			}
+			formatted := make([]string, len(args))
+			for i, arg := range args {
+				switch arg := arg.(type) {
+				case [32]byte:
+					formatted[i] = common.Hash(arg).String()
+				case []byte:
+					formatted[i] = hexutil.Encode(arg)
+				default:
+					formatted[i] = fmt.Sprint(arg)
+				}
+			}
+			return fmt.Sprintf("%s(%s)", customErr.Name, strings.Join(formatted, ", "))
+		}
+	}
+	return hexutil.Encode(data)
 }
 
 func For(ctx context.Context, rate time.Duration, cb func() (bool, error)) error {
@@ -0,0 +1,57 @@
+package wait
+
+import (
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/require"
+)
+
+const customErrorABI = `[{"type":"error","name":"InvalidProof","inputs":[{"name":"root","type":"bytes32"},{"name":"index","type":"uint256"}]}]`
+
+func encodeRevert(t *testing.T, sig string, typ string, value any) []byte {
+	argType, err := abi.NewType(typ, "", nil)
+	require.NoError(t, err)
+	args, err := abi.Arguments{{Type: argType}}.Pack(value)
+	require.NoError(t, err)
+	return append(crypto.Keccak256([]byte(sig))[:4], args...)
+}
+
+func TestDecodeRevertData(t *testing.T) {
+	customABI, err := abi.JSON(strings.NewReader(customErrorABI))
+	require.NoError(t, err)
+	customErr := customABI.Errors["InvalidProof"]
+	customArgs, err := customErr.Inputs.Pack(common.Hash{0xaa}, big.NewInt(3))
+	require.NoError(t, err)
+	customData := append(customErr.ID[:4:4], customArgs...)
+
+	tests := []struct {
+		name     string
+		data     []byte
+		abis     []*abi.ABI
+		expected string
+	}{
+		{"Error", encodeRevert(t, "Error(string)", "string", "not allowed"), nil, "not allowed"},
+		{"Panic", encodeRevert(t, "Panic(uint256)", "uint256", big.NewInt(0x11)), nil, "arithmetic underflow or overflow"},
+		{"CustomError", customData, []*abi.ABI{&customABI}, "InvalidProof(" + common.Hash{0xaa}.String() + ", 3)"},
+		{"CustomErrorWithoutABI", customData, nil, hexutil.Encode(customData)},
+		{"Unknown", []byte{0x01, 0x02, 0x03, 0x04, 0x05}, []*abi.ABI{&customABI}, "0x0102030405"},
+		{"Short", []byte{0x01}, nil, "0x01"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			require.Equal(t, test.expected, decodeRevertData(test.data, test.abis))
+		})
+	}
+}
+
+func TestRevertReason(t *testing.T) {
+	require.Equal(t, "not allowed", revertReason(jsonRawString(`{"output":"`+hexutil.Encode(encodeRevert(t, "Error(string)", "string", "not allowed"))+`","error":"execution reverted"}`), nil))
+	require.Equal(t, "out of gas", revertReason(`{"error":"out of gas"}`, nil))
+	require.Equal(t, "<unavailable: no trace>", revertReason("", nil))
+}
@@ -34,6 +34,9 @@ var (
 	ErrChainIDsSame                  = errors.New("L1 and L2 chain IDs must be different")
 	ErrL1ChainIDNotPositive          = errors.New("L1 chain ID must be non-zero and positive")
 	ErrL2ChainIDNotPositive          = errors.New("L2 chain ID must be non-zero and positive")
+	ErrDependencySetWithoutInterop   = errors.New("interop dependency set requires interop time to be set")
+	ErrDependencySetChainIDInvalid   = errors.New("interop dependency set chain IDs must be non-nil, non-zero and positive")
+	ErrDependencySetDuplicateChainID = errors.New("interop dependency set must not contain duplicate chain IDs")
 )
 
 type Genesis struct {
@@ -95,6 +98,10 @@ type Config struct {
 	// Active if InteropTime != nil && L2 block timestamp >= *InteropTime, inactive otherwise.
 	InteropTime *uint64 `json:"interop_time,omitempty"`
 
+	// InteropDependencySet is the set of L2 chain IDs that this chain accepts cross-chain messages from
+	// once Interop is active. Optional, and only valid if InteropTime is set.
+	InteropDependencySet []*big.Int `json:"interop_dependency_set,omitempty"`
+
 	// Note: below addresses are part of the block-derivation process,
 	// and required to be the same network-wide to stay in consensus.
 
@@ -271,6 +278,21 @@ func (cfg *Config) Check() error {
 	if cfg.L2ChainID.Sign() < 1 {
 		return ErrL2ChainIDNotPositive
 	}
+	if len(cfg.InteropDependencySet) > 0 {
+		if cfg.InteropTime == nil {
+			return ErrDependencySetWithoutInterop
+		}
+		seen := make(map[string]struct{}, len(cfg.InteropDependencySet))
+		for _, chainID := range cfg.InteropDependencySet {
+			if chainID == nil || chainID.Sign() < 1 {
+				return ErrDependencySetChainIDInvalid
+			}
+			if _, ok := seen[chainID.String()]; ok {
+				return ErrDependencySetDuplicateChainID
+			}
+			seen[chainID.String()] = struct{}{}
+		}
+	}
 	return nil
 }
 
@@ -308,6 +330,19 @@ func (c *Config) IsInterop(timestamp uint64) bool {
 	return c.InteropTime != nil && timestamp >= *c.InteropTime
 }
 
+// IsInDependencySet returns true if the given chain ID is part of the Interop dependency set of the chain.
+func (c *Config) IsInDependencySet(chainID *big.Int) bool {
+	if chainID == nil {
+		return false
+	}
+	for _, id := range c.InteropDependencySet {
+		if id.Cmp(chainID) == 0 {
+			return true
+		}
+	}
+	return false
+}
+
 // Description outputs a banner describing the important parts of rollup configuration in a human-readable form.
 // Optionally provide a mapping of L2 chain IDs to network names to label the L2 chain with if not unknown.
 // The config should be config.Check()-ed before creating a description.
@@ -340,6 +375,9 @@ func (c *Config) Description(l2Chains map[string]string) string {
 	banner += fmt.Sprintf("  - Eclipse: %s\n", fmtForkTimeOrUnset(c.EclipseTime))
 	banner += fmt.Sprintf("  - Fjord: %s\n", fmtForkTimeOrUnset(c.FjordTime))
 	banner += fmt.Sprintf("  - Interop: %s\n", fmtForkTimeOrUnset(c.InteropTime))
+	if len(c.InteropDependencySet) > 0 {
+		banner += fmt.Sprintf("  - Interop dependency set: %v\n", c.InteropDependencySet)
+	}
 	// Report the protocol version
 	banner += fmt.Sprintf("Node supports up to OP-Stack Protocol Version: %s\n", OPStackSupport)
 	return banner
@@ -370,6 +408,7 @@ func (c *Config) LogDescription(log log.Logger, l2Chains map[string]string) {
 		"eclipse_time", fmtForkTimeOrUnset(c.EclipseTime),
 		"fjord_time", fmtForkTimeOrUnset(c.FjordTime),
 		"interop_time", fmtForkTimeOrUnset(c.InteropTime),
+		"interop_dependency_set", c.InteropDependencySet,
 	)
 }
 
@@ -386,6 +386,38 @@ func TestConfig_Check(t *testing.T) {
 			modifier:    func(cfg *Config) { cfg.L2ChainID = big.NewInt(0) },
 			expectedErr: ErrL2ChainIDNotPositive,
 		},
+		{
+			name: "DependencySetWithoutInterop",
+			modifier: func(cfg *Config) {
+				cfg.InteropTime = nil
+				cfg.InteropDependencySet = []*big.Int{big.NewInt(10)}
+			},
+			expectedErr: ErrDependencySetWithoutInterop,
+		},
+		{
+			name: "DependencySetChainIdZero",
+			modifier: func(cfg *Config) {
+				cfg.InteropTime = new(uint64)
+				cfg.InteropDependencySet = []*big.Int{big.NewInt(10), big.NewInt(0)}
+			},
+			expectedErr: ErrDependencySetChainIDInvalid,
+		},
+		{
+			name: "DependencySetChainIdNil",
+			modifier: func(cfg *Config) {
+				cfg.InteropTime = new(uint64)
+				cfg.InteropDependencySet = []*big.Int{nil}
+			},
+			expectedErr: ErrDependencySetChainIDInvalid,
+		},
+		{
+			name: "DependencySetDuplicateChainId",
+			modifier: func(cfg *Config) {
+				cfg.InteropTime = new(uint64)
+				cfg.InteropDependencySet = []*big.Int{big.NewInt(10), big.NewInt(11), big.NewInt(10)}
+			},
+			expectedErr: ErrDependencySetDuplicateChainID,
+		},
 	}
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
@@ -397,6 +429,16 @@ func TestConfig_Check(t *testing.T) {
 	}
 }
 
+func TestIsInDependencySet(t *testing.T) {
+	cfg := randConfig()
+	cfg.InteropTime = new(uint64)
+	cfg.InteropDependencySet = []*big.Int{big.NewInt(10), big.NewInt(11)}
+	require.NoError(t, cfg.Check())
+	require.True(t, cfg.IsInDependencySet(big.NewInt(11)))
+	require.False(t, cfg.IsInDependencySet(big.NewInt(12)))
+	require.False(t, cfg.IsInDependencySet(nil))
+}
+
 func TestTimestampForBlock(t *testing.T) {
 	config := randConfig()
 
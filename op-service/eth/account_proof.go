@@ -3,6 +3,7 @@ package eth
 import (
 	"bytes"
 	"fmt"
+	"math/big"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
@@ -31,38 +32,65 @@ type AccountResult struct {
 	StorageProof []StorageProofEntry `json:"storageProof,omitempty"`
 }
 
-// Verify an account (and optionally storage) proof from the getProof RPC. See https://eips.ethereum.org/EIPS/eip-1186
-func (res *AccountResult) Verify(stateRoot common.Hash) error {
-	// verify storage proof values, if any, against the storage trie root hash of the account
-	for i, entry := range res.StorageProof {
-		// load all MPT nodes into a DB
-		db := memorydb.New()
-		for j, encodedNode := range entry.Proof {
-			nodeKey := encodedNode
-			if len(encodedNode) >= 32 { // small MPT nodes are not hashed
-				nodeKey = crypto.Keccak256(encodedNode)
-			}
-			if err := db.Put(nodeKey, encodedNode); err != nil {
-				return fmt.Errorf("failed to load storage proof node %d of storage value %d into mem db: %w", j, i, err)
-			}
-		}
-		path := crypto.Keccak256(entry.Key[:])
-		val, err := trie.VerifyProof(res.StorageHash, path, db)
-		if err != nil {
-			return fmt.Errorf("failed to verify storage value %d with key %s (path %x) in storage trie %s: %w", i, entry.Key, path, res.StorageHash, err)
-		}
-		if val == nil && entry.Value.ToInt().Cmp(common.Big0) == 0 { // empty storage is zero by default
-			continue
-		}
-		comparison, err := rlp.EncodeToBytes(entry.Value.ToInt().Bytes())
+// Verify the storage proof of the entry against the storage trie root hash of the account.
+// The proven value of the storage slot is returned, which matches the claimed value of the entry.
+func (e *StorageProofEntry) Verify(storageRoot common.Hash) (*big.Int, error) {
+	db, err := proofDB(e.Proof)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load storage proof of key %s: %w", e.Key, err)
+	}
+	path := crypto.Keccak256(e.Key[:])
+	val, err := trie.VerifyProof(storageRoot, path, db)
+	if err != nil {
+		return nil, fmt.Errorf("failed to verify storage value with key %s (path %x) in storage trie %s: %w", e.Key, path, storageRoot, err)
+	}
+	if val == nil && e.Value.ToInt().Cmp(common.Big0) == 0 { // empty storage is zero by default
+		return new(big.Int), nil
+	}
+	comparison, err := rlp.EncodeToBytes(e.Value.ToInt().Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode storage value with key %s (path %x) in storage trie %s: %w", e.Key, path, storageRoot, err)
+	}
+	if !bytes.Equal(val, comparison) {
+		return nil, fmt.Errorf("value in storage proof does not match proven value at key %s (path %x)", e.Key, path)
+	}
+	return new(big.Int).Set(e.Value.ToInt()), nil
+}
+
+// ProofBytes encodes the storage proof as a stream of the concatenated RLP-encoded proof nodes,
+// ordered from the root to the leaf, as consumed on-chain.
+func (e *StorageProofEntry) ProofBytes() []byte {
+	size := 0
+	for _, node := range e.Proof {
+		size += len(node)
+	}
+	out := make([]byte, 0, size)
+	for _, node := range e.Proof {
+		out = append(out, node...)
+	}
+	return out
+}
+
+// DecodeProofBytes splits a stream of concatenated RLP-encoded proof nodes, as produced by
+// [StorageProofEntry.ProofBytes], back into the individual nodes.
+func DecodeProofBytes(data []byte) ([]hexutil.Bytes, error) {
+	var proof []hexutil.Bytes
+	for len(data) > 0 {
+		kind, _, rest, err := rlp.Split(data)
 		if err != nil {
-			return fmt.Errorf("failed to encode storage value %d with key %s (path %x) in storage trie %s: %w", i, entry.Key, path, res.StorageHash, err)
+			return nil, fmt.Errorf("invalid proof node %d: %w", len(proof), err)
 		}
-		if !bytes.Equal(val, comparison) {
-			return fmt.Errorf("value %d in storage proof does not match proven value at key %s (path %x)", i, entry.Key, path)
+		if kind != rlp.List {
+			return nil, fmt.Errorf("invalid proof node %d: expected list but got %v", len(proof), kind)
 		}
+		proof = append(proof, common.CopyBytes(data[:len(data)-len(rest)]))
+		data = rest
 	}
+	return proof, nil
+}
 
+// VerifyAccount verifies the account proof against the given state root, without verifying the storage proofs.
+func (res *AccountResult) VerifyAccount(stateRoot common.Hash) error {
 	accountClaimed := []any{uint64(res.Nonce), res.Balance.ToInt().Bytes(), res.StorageHash, res.CodeHash}
 	accountClaimedValue, err := rlp.EncodeToBytes(accountClaimed)
 	if err != nil {
@@ -70,15 +98,9 @@ func (res *AccountResult) Verify(stateRoot common.Hash) error {
 	}
 
 	// create a db with all account trie nodes
-	db := memorydb.New()
-	for i, encodedNode := range res.AccountProof {
-		nodeKey := encodedNode
-		if len(encodedNode) >= 32 { // small MPT nodes are not hashed
-			nodeKey = crypto.Keccak256(encodedNode)
-		}
-		if err := db.Put(nodeKey, encodedNode); err != nil {
-			return fmt.Errorf("failed to load account proof node %d into mem db: %w", i, err)
-		}
+	db, err := proofDB(res.AccountProof)
+	if err != nil {
+		return fmt.Errorf("failed to load account proof: %w", err)
 	}
 	path := crypto.Keccak256(res.Address[:])
 	accountProofValue, err := trie.VerifyProof(stateRoot, path, db)
@@ -91,5 +113,31 @@ func (res *AccountResult) Verify(stateRoot common.Hash) error {
 			"  claimed: %x\n"+
 			"  proof:   %x", accountClaimedValue, accountProofValue)
 	}
-	return err
+	return nil
+}
+
+// Verify an account (and optionally storage) proof from the getProof RPC. See https://eips.ethereum.org/EIPS/eip-1186
+func (res *AccountResult) Verify(stateRoot common.Hash) error {
+	// verify storage proof values, if any, against the storage trie root hash of the account
+	for i, entry := range res.StorageProof {
+		if _, err := entry.Verify(res.StorageHash); err != nil {
+			return fmt.Errorf("invalid storage proof %d: %w", i, err)
+		}
+	}
+	return res.VerifyAccount(stateRoot)
+}
+
+// proofDB loads all MPT nodes of a proof into a DB
+func proofDB(proof []hexutil.Bytes) (*memorydb.Database, error) {
+	db := memorydb.New()
+	for i, encodedNode := range proof {
+		nodeKey := encodedNode
+		if len(encodedNode) >= 32 { // small MPT nodes are not hashed
+			nodeKey = crypto.Keccak256(encodedNode)
+		}
+		if err := db.Put(nodeKey, encodedNode); err != nil {
+			return nil, fmt.Errorf("failed to load proof node %d into mem db: %w", i, err)
+		}
+	}
+	return db, nil
 }
@@ -58,6 +58,47 @@ func TestAccountResult_Verify(t *testing.T) {
 	require.NotNil(t, result.Verify(goodRoot), "does not verify against bad proof")
 }
 
+func TestStorageProofEntry_Verify(t *testing.T) {
+	result := makeResult(t)
+	value, err := result.StorageProof[0].Verify(result.StorageHash)
+	require.NoError(t, err, "verifies against storage hash")
+	require.Equal(t, common.HexToAddress("0x715b7219d986641df9efd9c7ef01218d528e19ec").Big(), value)
+
+	_, err = result.StorageProof[0].Verify(goodRoot)
+	require.Error(t, err, "does not verify against other storage root")
+
+	result.StorageProof[0].Value = hexutil.Big(*big.NewInt(1))
+	_, err = result.StorageProof[0].Verify(result.StorageHash)
+	require.Error(t, err, "does not verify against wrong claimed value")
+}
+
+func TestStorageProofEntry_ProofBytes(t *testing.T) {
+	entry := makeResult(t).StorageProof[0]
+	data := entry.ProofBytes()
+	require.Len(t, data, len(entry.Proof[0])+len(entry.Proof[1]))
+
+	proof, err := DecodeProofBytes(data)
+	require.NoError(t, err)
+	require.Equal(t, entry.Proof, proof)
+
+	_, err = DecodeProofBytes(data[:len(data)-1])
+	require.Error(t, err, "truncated proof")
+	_, err = DecodeProofBytes([]byte{0x80})
+	require.Error(t, err, "proof nodes must be lists")
+
+	proof, err = DecodeProofBytes(nil)
+	require.NoError(t, err)
+	require.Empty(t, proof)
+}
+
+func TestAccountResult_VerifyAccount(t *testing.T) {
+	result := makeResult(t)
+	result.StorageProof[0].Proof[0][0] = 0x00
+	require.NoError(t, result.VerifyAccount(goodRoot), "verifies account without storage proofs")
+	result.Nonce++
+	require.Error(t, result.VerifyAccount(goodRoot), "does not verify against bad account values")
+}
+
 func FuzzAccountResult_StorageProof(f *testing.F) {
 	f.Fuzz(func(t *testing.T, key []byte, value []byte) {
 		result := makeResult(t)
@@ -80,6 +121,22 @@ func FuzzAccountResult_AccountProof(f *testing.F) {
 	})
 }
 
+func FuzzDecodeProofBytes(f *testing.F) {
+	var result AccountResult
+	require.NoError(f, json.Unmarshal([]byte(resultData), &result))
+	f.Add(result.StorageProof[0].ProofBytes())
+	f.Add([]byte{0xc0})
+	f.Add([]byte{0xf9, 0xff, 0xff})
+	f.Fuzz(func(t *testing.T, data []byte) {
+		proof, err := DecodeProofBytes(data)
+		if err != nil {
+			return
+		}
+		entry := StorageProofEntry{Proof: proof}
+		require.Equal(t, data, append([]byte{}, entry.ProofBytes()...), "decoded proof re-encodes to the same bytes")
+	})
+}
+
 func makeResult(t *testing.T) AccountResult {
 	var result AccountResult
 	require.NoError(t, json.Unmarshal([]byte(resultData), &result))
@@ -0,0 +1,151 @@
+package eth_test
+
+import (
+	"bytes"
+	"fmt"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethereum-optimism/optimism/op-service/eth"
+	"github.com/ethereum-optimism/optimism/op-service/testutils"
+)
+
+// sequentialKeys returns the storage keys of the first n slots.
+func sequentialKeys(n int) []common.Hash {
+	keys := make([]common.Hash, n)
+	for i := range keys {
+		keys[i] = common.BigToHash(big.NewInt(int64(i)))
+	}
+	return keys
+}
+
+// extensionKeys returns the keys of two slots whose trie paths share the first nibble,
+// such that the storage trie holding only these slots has an extension node at its root.
+func extensionKeys() []common.Hash {
+	first := common.BigToHash(common.Big0)
+	nibble := crypto.Keccak256(first[:])[0] >> 4
+	for i := int64(1); ; i++ {
+		key := common.BigToHash(big.NewInt(i))
+		if crypto.Keccak256(key[:])[0]>>4 == nibble {
+			return []common.Hash{first, key}
+		}
+	}
+}
+
+type proofFixture struct {
+	entry       eth.StorageProofEntry
+	storageRoot common.Hash
+}
+
+// proofFixtures returns storage proofs of a single leaf, of a trie with an extension node
+// at its root, and of a deep trie of branch nodes.
+func proofFixtures(t testing.TB) []proofFixture {
+	var fixtures []proofFixture
+	for _, keys := range [][]common.Hash{sequentialKeys(1), extensionKeys(), sequentialKeys(10_000)} {
+		result, _ := testutils.MakeProvenAccount(t, keys)
+		fixtures = append(fixtures, proofFixture{entry: result.StorageProof[0], storageRoot: result.StorageHash})
+	}
+	return fixtures
+}
+
+// copyProof returns a deep copy of the proof nodes, such that fixtures can be mutated.
+func copyProof(proof []hexutil.Bytes) []hexutil.Bytes {
+	out := make([]hexutil.Bytes, len(proof))
+	for i, node := range proof {
+		out[i] = common.CopyBytes(node)
+	}
+	return out
+}
+
+func FuzzStorageProofEntry_Verify(f *testing.F) {
+	fixtures := proofFixtures(f)
+	for i, fixture := range fixtures {
+		for j, node := range fixture.entry.Proof {
+			f.Add(uint8(i), uint8(j), uint16(0), byte(0x00), uint16(0), uint16(0))
+			f.Add(uint8(i), uint8(j), uint16(len(node)/2), byte(0xff), uint16(0), uint16(0))
+			f.Add(uint8(i), uint8(j), uint16(len(node)-1), byte(0x80), uint16(len(node)/2), uint16(0))
+			// oversized nodes, with trailing data beyond the RLP list
+			f.Add(uint8(i), uint8(j), uint16(0), node[0], uint16(0), uint16(1))
+			f.Add(uint8(i), uint8(j), uint16(0), node[0], uint16(0), uint16(1024))
+		}
+	}
+	f.Fuzz(func(t *testing.T, fixtureIdx uint8, nodeIdx uint8, offset uint16, replacement byte, truncate uint16, extend uint16) {
+		fixture := fixtures[int(fixtureIdx)%len(fixtures)]
+		entry := fixture.entry
+		entry.Proof = copyProof(entry.Proof)
+		idx := int(nodeIdx) % len(entry.Proof)
+		node := entry.Proof[idx]
+		node[int(offset)%len(node)] = replacement
+		node = node[:len(node)-int(truncate)%len(node)]
+		node = append(node, bytes.Repeat([]byte{replacement}, int(extend))...)
+		if bytes.Equal(node, fixture.entry.Proof[idx]) {
+			return // not a mutation
+		}
+		entry.Proof[idx] = node
+
+		// the proof must be rejected, without panicking
+		_, err := entry.Verify(fixture.storageRoot)
+		require.Error(t, err, "mutated proof must not verify")
+
+		// the byte stream may split trailing data off an oversized node, recovering the genuine node
+		if proof, err := eth.DecodeProofBytes(entry.ProofBytes()); err == nil {
+			requireRejected(t, fixture, proof)
+		}
+	})
+}
+
+func FuzzStorageProofEntry_VerifyDecoded(f *testing.F) {
+	fixtures := proofFixtures(f)
+	for i, fixture := range fixtures {
+		proof := fixture.entry.Proof
+		f.Add(uint8(i), fixture.entry.ProofBytes())
+		f.Add(uint8(i), (&eth.StorageProofEntry{Proof: proof[:len(proof)-1]}).ProofBytes())
+		f.Add(uint8(i), (&eth.StorageProofEntry{Proof: proof[1:]}).ProofBytes())
+		f.Add(uint8(i), fixtures[(i+1)%len(fixtures)].entry.ProofBytes())
+	}
+	f.Add(uint8(0), []byte{0xc0})
+	f.Fuzz(func(t *testing.T, fixtureIdx uint8, data []byte) {
+		proof, err := eth.DecodeProofBytes(data)
+		if err != nil {
+			return
+		}
+		requireRejected(t, fixtures[int(fixtureIdx)%len(fixtures)], proof)
+	})
+}
+
+// requireRejected requires the proof to fail verification against the fixture, unless it includes
+// all genuine proof nodes: nodes are looked up by hash, so any additional nodes are ignored.
+func requireRejected(t *testing.T, fixture proofFixture, proof []hexutil.Bytes) {
+	entry := fixture.entry
+	entry.Proof = proof
+	if _, err := entry.Verify(fixture.storageRoot); err == nil {
+		for _, node := range fixture.entry.Proof {
+			require.Contains(t, proof, node, "proof without the genuine nodes must not verify")
+		}
+	}
+}
+
+func BenchmarkAccountResult_Verify(b *testing.B) {
+	for _, slots := range []int{1, 10_000, 1_000_000} {
+		var result *eth.AccountResult
+		var stateRoot common.Hash
+		b.Run(fmt.Sprintf("slots-%d", slots), func(b *testing.B) {
+			// build the fixture once the sub-benchmark is selected, rather than for every b.N probe
+			if result == nil {
+				result, stateRoot = testutils.MakeProvenAccount(b, sequentialKeys(slots))
+			}
+			b.ReportMetric(float64(len(result.StorageProof[0].Proof)), "storage-nodes")
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if err := result.Verify(stateRoot); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,66 @@
+package testutils
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum-optimism/optimism/op-service/eth"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/ethereum/go-ethereum/trie"
+	"github.com/stretchr/testify/require"
+)
+
+// ProofCollector collects the encoded nodes written by [trie.Trie.Prove], in root to leaf order.
+type ProofCollector []hexutil.Bytes
+
+func (p *ProofCollector) Put(_ []byte, value []byte) error {
+	*p = append(*p, common.CopyBytes(value))
+	return nil
+}
+
+func (p *ProofCollector) Delete(_ []byte) error {
+	return errors.New("unsupported")
+}
+
+// MakeProvenAccount builds a state with a single account, holding the value i+1 in the storage slot of keys[i].
+// It returns an account result with a proof of the last storage slot, if any, along with the state root.
+func MakeProvenAccount(t testing.TB, keys []common.Hash) (*eth.AccountResult, common.Hash) {
+	storage := trie.NewEmpty(trie.NewDatabase(rawdb.NewMemoryDatabase(), nil))
+	for i, key := range keys {
+		value, err := rlp.EncodeToBytes(big.NewInt(int64(i + 1)).Bytes())
+		require.NoError(t, err)
+		storage.MustUpdate(crypto.Keccak256(key[:]), value)
+	}
+
+	result := &eth.AccountResult{
+		Address:     common.Address{0xaa},
+		Balance:     (*hexutil.Big)(big.NewInt(1)),
+		CodeHash:    crypto.Keccak256Hash([]byte{0x00}),
+		Nonce:       1,
+		StorageHash: storage.Hash(),
+	}
+	if len(keys) > 0 {
+		key := keys[len(keys)-1]
+		var storageProof ProofCollector
+		require.NoError(t, storage.Prove(crypto.Keccak256(key[:]), &storageProof))
+		result.StorageProof = []eth.StorageProofEntry{{
+			Key:   key,
+			Value: hexutil.Big(*big.NewInt(int64(len(keys)))),
+			Proof: storageProof,
+		}}
+	}
+
+	account, err := rlp.EncodeToBytes([]any{uint64(result.Nonce), result.Balance.ToInt().Bytes(), result.StorageHash, result.CodeHash})
+	require.NoError(t, err)
+	state := trie.NewEmpty(trie.NewDatabase(rawdb.NewMemoryDatabase(), nil))
+	state.MustUpdate(crypto.Keccak256(result.Address[:]), account)
+	var accountProof ProofCollector
+	require.NoError(t, state.Prove(crypto.Keccak256(result.Address[:]), &accountProof))
+	result.AccountProof = accountProof
+	return result, state.Hash()
+}